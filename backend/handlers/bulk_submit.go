@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"backend/logging"
+	"backend/models"
+	"backend/services"
+)
+
+// maxBulkURLs caps how many URLs a single /submit/bulk request may carry.
+const maxBulkURLs = 50
+
+// HandleBulkSubmit handles POST /submit/bulk: it runs the same validation,
+// cache/db lookup and enqueue pipeline as HandleSubmit for each URL, then
+// groups the resulting tasks under a single group_uuid so a UI can track
+// batch progress via GET /groups/{group_uuid}.
+func HandleBulkSubmit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.BulkSubmitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.URLs) == 0 {
+		http.Error(w, "At least one URL is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.URLs) > maxBulkURLs {
+		http.Error(w, fmt.Sprintf("At most %d URLs are allowed per bulk submission", maxBulkURLs), http.StatusBadRequest)
+		return
+	}
+
+	requestID := logging.RequestIDFromContext(r.Context())
+	logging.WithFields("", "", "bulk_submit").WithField("request_id", requestID).Info("received")
+
+	results := make([]models.BulkSubmitItemResult, 0, len(req.URLs))
+	taskUUIDs := make([]string, 0, len(req.URLs))
+	seen := make(map[string]models.BulkSubmitItemResult)
+
+	for _, urlStr := range req.URLs {
+		// Same URL submitted twice in this request - reuse its result
+		// instead of creating a second task for it.
+		if prior, ok := seen[urlStr]; ok {
+			results = append(results, prior)
+			continue
+		}
+
+		resp, cached, subErr := processSubmission(r.Context(), urlStr, requestID)
+		item := models.BulkSubmitItemResult{URL: urlStr, Cached: cached}
+		if subErr != nil {
+			item.Status = "error"
+			item.Error = subErr.Error()
+		} else {
+			item.Status = resp.Status
+			item.UUID = resp.UUID
+			if item.UUID != "" {
+				taskUUIDs = append(taskUUIDs, item.UUID)
+			}
+		}
+
+		seen[urlStr] = item
+		results = append(results, item)
+	}
+
+	group := models.TaskGroup{
+		GroupUUID: uuid.New().String(),
+		GroupName: req.GroupName,
+		TaskUUIDs: taskUUIDs,
+		CreatedAt: time.Now().UnixMilli(),
+	}
+	if err := services.SaveGroup(group); err != nil {
+		log.Printf("Failed to save task group %s: %v", group.GroupUUID, err)
+	}
+
+	logging.WithFields("", "", "bulk_submit").WithField("request_id", requestID).Info("forwarded")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.BulkSubmitResponse{
+		GroupUUID: group.GroupUUID,
+		Results:   results,
+	})
+}
@@ -1,21 +1,26 @@
 package handlers
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
+	"strings"
 
+	"backend/logging"
 	"backend/models"
+	"backend/services"
 	"backend/websocket"
 )
 
-// HandleChat handles chat requests by forwarding them to the LLM server
+// HandleChat starts a streaming chat turn. It returns 202 immediately with
+// the task UUID, then streams token deltas from the LLM server to the
+// WebSocket client subscribed to that UUID as chat_token frames, finishing
+// with a chat_response frame carrying the full text. If the subscribed
+// client disconnects, the outbound request to the LLM server is cancelled.
 func HandleChat(w http.ResponseWriter, r *http.Request, hub *websocket.Hub) {
-	fmt.Println("Got chat request")
-
 	if r.Method != "POST" {
 		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
 		return
@@ -38,76 +43,118 @@ func HandleChat(w http.ResponseWriter, r *http.Request, hub *websocket.Hub) {
 		return
 	}
 
-	fmt.Printf("Forwarding chat request for UUID: %s\n", req.UUID)
+	requestID := logging.RequestIDFromContext(r.Context())
+	logging.WithFields(req.UUID, "", "chat").WithField("request_id", requestID).Info("received")
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	hub.RegisterCancel(req.UUID, cancel)
+
+	go streamChatResponse(streamCtx, hub, req, requestID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(models.ChatResponse{
+		UUID:    req.UUID,
+		Success: true,
+	})
+}
+
+// streamChatResponse opens a Server-Sent-Events request against the LLM
+// server and relays each token delta to the WebSocket client subscribed to
+// req.UUID as it arrives, then broadcasts a final chat_response frame with
+// the accumulated text.
+func streamChatResponse(ctx context.Context, hub *websocket.Hub, req models.ChatRequest, requestID string) {
+	defer hub.UnregisterCancel(req.UUID)
 
-	// Forward request to LLM server
-	llmServerURL := "http://llm-server:8000/chat"
+	llmServerURL := "http://llm-server:8000/chat/stream"
 	requestBody, err := json.Marshal(map[string]interface{}{
 		"uuid":    req.UUID,
 		"message": req.Message,
 	})
 	if err != nil {
 		log.Printf("Failed to marshal chat request: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	resp, err := http.Post(llmServerURL, "application/json", bytes.NewBuffer(requestBody))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, llmServerURL, bytes.NewBuffer(requestBody))
 	if err != nil {
-		log.Printf("Failed to forward request to LLM server: %v", err)
-		http.Error(w, "Failed to process chat request", http.StatusInternalServerError)
+		log.Printf("Failed to build chat stream request: %v", err)
 		return
 	}
-	defer resp.Body.Close()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
 
-	// Read response from LLM server
-	responseBody, err := ioutil.ReadAll(resp.Body)
+	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
-		log.Printf("Failed to read LLM server response: %v", err)
-		http.Error(w, "Failed to read response", http.StatusInternalServerError)
+		if ctx.Err() != nil {
+			log.Printf("Chat stream for UUID %s cancelled before it started", req.UUID)
+			return
+		}
+		log.Printf("Failed to open chat stream to LLM server: %v", err)
 		return
 	}
+	defer resp.Body.Close()
+
+	logging.WithFields(req.UUID, "", "chat").WithField("request_id", requestID).Info("forwarded")
 
-	// Check if LLM server returned an error
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("LLM server returned error: %d - %s", resp.StatusCode, string(responseBody))
-		http.Error(w, fmt.Sprintf("LLM server error: %s", string(responseBody)), resp.StatusCode)
+		log.Printf("LLM server returned status %d for chat stream", resp.StatusCode)
 		return
 	}
 
-	// Parse LLM server response
-	var llmResponse map[string]interface{}
-	if err := json.Unmarshal(responseBody, &llmResponse); err != nil {
-		log.Printf("Failed to parse LLM server response: %v", err)
-		http.Error(w, "Failed to parse response", http.StatusInternalServerError)
-		return
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			log.Printf("Chat stream for UUID %s cancelled", req.UUID)
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		delta := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if delta == "[DONE]" {
+			break
+		}
+		full.WriteString(delta)
+
+		tokenMessage := models.WSMessage{
+			Type: "chat_token",
+			Payload: map[string]interface{}{
+				"uuid":  req.UUID,
+				"delta": delta,
+				"done":  false,
+			},
+		}
+		if messageBytes, err := json.Marshal(tokenMessage); err == nil {
+			services.BroadcastToUUID(hub, req.UUID, messageBytes)
+		} else {
+			log.Printf("Failed to marshal chat_token message: %v", err)
+		}
 	}
 
-	// Create response for frontend
-	response := models.ChatResponse{
-		UUID:     req.UUID,
-		Response: llmResponse["response"].(string),
-		Success:  true,
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		log.Printf("Error reading chat stream for UUID %s: %v", req.UUID, err)
 	}
 
-	// Broadcast chat response to WebSocket clients
+	response := full.String()
+
 	chatMessage := models.WSMessage{
 		Type: "chat_response",
 		Payload: map[string]interface{}{
 			"uuid":     req.UUID,
-			"response": llmResponse["response"].(string),
+			"response": response,
 			"success":  true,
 		},
 	}
 
 	if messageBytes, err := json.Marshal(chatMessage); err == nil {
-		hub.GetBroadcastChannel() <- messageBytes
-		fmt.Printf("Broadcasted chat response to WebSocket clients: %s\n", string(messageBytes))
+		services.BroadcastWS(hub, messageBytes)
+		logging.WithFields(req.UUID, "", "chat").WithField("request_id", requestID).Info("broadcast")
 	} else {
 		log.Printf("Failed to marshal WebSocket chat message: %v", err)
 	}
-
-	// Return response to frontend
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-} 
\ No newline at end of file
+}
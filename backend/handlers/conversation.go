@@ -2,18 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
 
+	"backend/logging"
 	"backend/models"
 	"backend/services"
 )
 
 // HandleConversationUpdate handles conversation update requests
 func HandleConversationUpdate(w http.ResponseWriter, r *http.Request) {
-	fmt.Println("Got conversation update request")
-
 	if r.Method != "POST" {
 		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
 		return
@@ -35,7 +33,8 @@ func HandleConversationUpdate(w http.ResponseWriter, r *http.Request) {
 		req.Conversation = []models.ConversationEntry{} // Initialize empty conversation
 	}
 
-	fmt.Printf("Updating conversation for UUID: %s with %d entries\n", req.UUID, len(req.Conversation))
+	requestID := logging.RequestIDFromContext(r.Context())
+	logging.WithFields(req.UUID, "", "conversation").WithField("request_id", requestID).WithField("entries", len(req.Conversation)).Info("received")
 
 	// 1. Update conversation in the database
 	conversationJSON, err := json.Marshal(req)
@@ -45,7 +44,7 @@ func HandleConversationUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = services.UpdateConversationInDBService(req.UUID, string(conversationJSON))
+	err = services.UpdateConversationInDBService(r.Context(), req.UUID, string(conversationJSON))
 	if err != nil {
 		log.Printf("Failed to update conversation in database: %v", err)
 		http.Error(w, "Failed to update conversation in database", http.StatusInternalServerError)
@@ -55,11 +54,10 @@ func HandleConversationUpdate(w http.ResponseWriter, r *http.Request) {
 	// 2. Check if the article is currently cached and update cache if so
 	err = services.UpdateConversationInCache(req.UUID, req.Conversation)
 	if err != nil {
-		// This is not a critical error - the article might not be cached
-		fmt.Printf("Warning: Could not update conversation in cache: %v\n", err)
-		fmt.Printf("This is normal if the article is not currently cached\n")
+		// Not a critical error - the article might simply not be cached.
+		log.Printf("Could not update conversation in cache for UUID %s: %v", req.UUID, err)
 	} else {
-		fmt.Printf("Successfully updated conversation in cache for UUID: %s\n", req.UUID)
+		logging.WithFields(req.UUID, "", "conversation").WithField("request_id", requestID).Info("cached")
 	}
 
 	// 3. Return success response
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"backend/models"
+	"backend/services"
+)
+
+// HandleGroupStatus handles GET /groups/{group_uuid}, aggregating the
+// current status of every task submitted together via HandleBulkSubmit.
+func HandleGroupStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupUUID := strings.TrimPrefix(r.URL.Path, "/groups/")
+	if groupUUID == "" || strings.Contains(groupUUID, "/") {
+		http.Error(w, "Invalid group UUID", http.StatusBadRequest)
+		return
+	}
+
+	group, err := services.GetGroup(groupUUID)
+	if err != nil {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	tasks := make([]models.TaskResponse, 0, len(group.TaskUUIDs))
+	for _, taskUUID := range group.TaskUUIDs {
+		status, err := services.GetTaskStatus(taskUUID)
+		if err != nil {
+			log.Printf("Failed to get status for task %s in group %s: %v", taskUUID, groupUUID, err)
+			status = "unknown"
+		}
+		tasks = append(tasks, models.TaskResponse{Status: status, UUID: taskUUID})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.GroupStatusResponse{
+		GroupUUID: group.GroupUUID,
+		GroupName: group.GroupName,
+		Tasks:     tasks,
+	})
+}
@@ -39,52 +39,23 @@ func HandleStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If task is done, also return the cached result
+	// If task is done, also return the cached result, resolved via the
+	// uuid_url secondary index instead of scanning every url_task:* mapping.
 	var response models.TaskResponse
 	if status == "done" {
-		// Find the URL for this task by searching through url_task mappings
-		urlTaskKeys, err := services.GetAllURLTaskKeys()
-		if err != nil {
-			log.Printf("Failed to get URL task keys: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-
-		var taskURL string
-		for _, key := range urlTaskKeys {
-			urlTaskData, err := services.GetRedisClient().Get(services.GetContext(), key).Result()
-			if err != nil {
-				continue
-			}
-			var urlTask models.URLTaskMapping
-			if err := json.Unmarshal([]byte(urlTaskData), &urlTask); err != nil {
-				continue
-			}
-			if urlTask.UUID == taskUUID {
-				taskURL = key[9:] // Remove "url_task:" prefix
-				break
-			}
-		}
-
-		if taskURL != "" {
-			// Get cached result
-			if cachedResult, err := services.CheckCache(taskURL); err == nil {
+		if result, err := services.GetArticleByUUIDFromCache(taskUUID); err == nil {
+			if resultJSON, err := json.Marshal(result); err == nil {
 				response = models.TaskResponse{
 					Status: status,
 					UUID:   taskUUID,
-					Result: cachedResult,
+					Result: string(resultJSON),
 				}
 			} else {
-				response = models.TaskResponse{
-					Status: status,
-					UUID:   taskUUID,
-				}
+				log.Printf("Failed to marshal cached result for UUID %s: %v", taskUUID, err)
+				response = models.TaskResponse{Status: status, UUID: taskUUID}
 			}
 		} else {
-			response = models.TaskResponse{
-				Status: status,
-				UUID:   taskUUID,
-			}
+			response = models.TaskResponse{Status: status, UUID: taskUUID}
 		}
 	} else {
 		response = models.TaskResponse{
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,10 +11,16 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"backend/logging"
 	"backend/models"
 	"backend/services"
+	"backend/services/urlguard"
 )
 
+// guard rejects submitted URLs that resolve to private/internal addresses
+// (SSRF) before the backend fetches them.
+var guard = urlguard.NewFromEnv()
+
 // validateURL checks if the provided string is a valid HTTPS URL
 func validateURL(urlStr string) error {
 	// Check if URL is empty
@@ -40,11 +47,11 @@ func validateURL(urlStr string) error {
 	return nil
 }
 
-// checkURLAccessibility makes an HTTP HEAD request to check if the URL is accessible
+// checkURLAccessibility makes an HTTP HEAD request to check if the URL is
+// accessible. The request goes through guard's client so every redirect
+// hop is re-checked against private/internal addresses (SSRF).
 func checkURLAccessibility(urlStr string) error {
-	client := &http.Client{
-		Timeout: 10 * time.Second, // 10 second timeout
-	}
+	client := guard.Client(10 * time.Second)
 
 	req, err := http.NewRequest("HEAD", urlStr, nil)
 	if err != nil {
@@ -70,8 +77,6 @@ func checkURLAccessibility(urlStr string) error {
 
 // HandleSubmit handles article submission requests
 func HandleSubmit(w http.ResponseWriter, r *http.Request) {
-	fmt.Println("Got request")
-
 	if r.Method != "POST" {
 		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
 		return
@@ -83,125 +88,133 @@ func HandleSubmit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate URL format (HTTPS only)
-	if err := validateURL(req.URL); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	requestID := logging.RequestIDFromContext(r.Context())
+	logging.WithFields("", req.URL, "submit").WithField("request_id", requestID).Info("received")
+
+	resp, _, subErr := processSubmission(r.Context(), req.URL, requestID)
+	if subErr != nil {
+		http.Error(w, subErr.message, subErr.status)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// submitError carries the HTTP status processSubmission wants the caller
+// to respond with alongside the user-facing message.
+type submitError struct {
+	status  int
+	message string
+}
+
+func (e *submitError) Error() string {
+	return e.message
+}
+
+// processSubmission runs the full submission pipeline for a single URL:
+// validation, SSRF/accessibility checks, cache/db lookup, in-flight task
+// dedup, and queueing a new task. It is shared by HandleSubmit and
+// HandleBulkSubmit so both go through the same rules. cached reports
+// whether the response was served from cache/db rather than a live task.
+func processSubmission(ctx context.Context, urlStr string, requestID string) (resp models.TaskResponse, cached bool, subErr *submitError) {
+	// Validate URL format (HTTPS only)
+	if err := validateURL(urlStr); err != nil {
+		return models.TaskResponse{}, false, &submitError{http.StatusBadRequest, err.Error()}
+	}
+
+	// Reject URLs that resolve to private/internal addresses (SSRF)
+	if err := guard.CheckURL(urlStr); err != nil {
+		log.Printf("Rejected SSRF-unsafe URL %s: %v", urlStr, err)
+		return models.TaskResponse{}, false, &submitError{http.StatusBadRequest, "The provided link points to a disallowed address"}
+	}
+
 	// Check URL accessibility (returns 200)
-	if err := checkURLAccessibility(req.URL); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	if err := checkURLAccessibility(urlStr); err != nil {
+		return models.TaskResponse{}, false, &submitError{http.StatusBadRequest, err.Error()}
 	}
 
 	// 1. Check if the URL has already been processed and cached
-	cachedResult, err := services.CheckCache(req.URL)
-	if err == nil {
-		// URL is cached, return the cached result
-		fmt.Printf("Cache hit for URL: %s\n", req.URL)
-		fmt.Printf("cachedResult: %v\n", cachedResult)
-		
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(models.TaskResponse{
-			Status: "done",
-			Result: cachedResult,
-		})
-		return
+	if cachedResult, err := services.CheckCache(urlStr); err == nil {
+		logging.WithFields("", urlStr, "submit").WithField("request_id", requestID).Info("cached")
+		return models.TaskResponse{Status: "done", Result: cachedResult}, true, nil
 	}
 
 	// 1.5. URL is not cached - Check db-service (Postgres) for the article
-	dbResult, err := services.GetArticleFromDBService(req.URL)
-	if err == nil && dbResult != "" {
-		// Cache in Redis for next time (set TTL)
-		fmt.Printf("Cache miss for URL: %s | Retrieved from db-service | setting cache for 1 minute\n", req.URL)
-		services.SetCache(req.URL, dbResult, 1*time.Minute)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(models.TaskResponse{
-			Status: "done",
-			Result: dbResult,
-		})
-		return
+	if dbResult, err := services.GetArticleFromDBService(ctx, urlStr); err == nil && dbResult != "" {
+		services.SetCache(urlStr, dbResult, 1*time.Minute)
+		logging.WithFields("", urlStr, "submit").WithField("request_id", requestID).Info("cached_from_db")
+		return models.TaskResponse{Status: "done", Result: dbResult}, true, nil
 	} else {
-		fmt.Printf("Error retrieving article from db-service: %v\n", err)
+		log.Printf("Failed to retrieve article from db-service for %s: %v", urlStr, err)
 	}
 
 	// 2. Check if there's already a task in progress for this URL
-	urlTaskData, err := services.GetURLTaskMapping(req.URL)
-	if err == nil {
-		// URL is already being processed, return existing task info
+	if urlTaskData, err := services.GetURLTaskMapping(urlStr); err == nil {
 		var urlTask models.URLTaskMapping
 		if err := json.Unmarshal([]byte(urlTaskData), &urlTask); err != nil {
 			log.Printf("Failed to unmarshal URL task mapping: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
+			return models.TaskResponse{}, false, &submitError{http.StatusInternalServerError, "Internal server error"}
 		}
 
-		fmt.Printf("Task already in progress for URL: %s, UUID: %s, Status: %s\n", req.URL, urlTask.UUID, urlTask.Status)
-		
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(models.TaskResponse{
-			Status: urlTask.Status,
-			UUID:   urlTask.UUID,
-		})
-		return
+		logging.WithFields(urlTask.UUID, urlStr, "submit").WithField("request_id", requestID).Info("already_in_progress")
+		return models.TaskResponse{Status: urlTask.Status, UUID: urlTask.UUID}, false, nil
 	}
 
 	// 3. URL not cached and no task in progress, create new task
 	taskUUID := uuid.New().String()
-	fmt.Printf("Creating new task for URL: %s, UUID: %s\n", req.URL, taskUUID)
+	logging.WithFields(taskUUID, urlStr, "submit").WithField("request_id", requestID).Info("created")
 
 	// 4. Store task status as "pending"
 	if err := services.SetTaskStatus(taskUUID, "pending"); err != nil {
 		log.Printf("Failed to set task status: %v", err)
-		http.Error(w, "Failed to create task", http.StatusInternalServerError)
-		return
+		return models.TaskResponse{}, false, &submitError{http.StatusInternalServerError, "Failed to create task"}
 	}
 
 	// 5. Immediately cache the URL-to-task mapping to prevent duplicates
+	createdAt := time.Now().UnixMilli()
 	urlTaskMapping := models.URLTaskMapping{
-		UUID:   taskUUID,
-		Status: "pending",
+		UUID:      taskUUID,
+		Status:    "pending",
+		CreatedAt: createdAt,
 	}
 	urlTaskMappingData, err := json.Marshal(urlTaskMapping)
 	if err != nil {
 		log.Printf("Failed to marshal URL task mapping: %v", err)
-		http.Error(w, "Failed to create task", http.StatusInternalServerError)
-		return
+		return models.TaskResponse{}, false, &submitError{http.StatusInternalServerError, "Failed to create task"}
 	}
 
-	if err := services.SetURLTaskMapping(req.URL, urlTaskMappingData); err != nil {
+	if err := services.SetURLTaskMapping(urlStr, urlTaskMappingData); err != nil {
 		log.Printf("Failed to set URL task mapping: %v", err)
-		http.Error(w, "Failed to create task", http.StatusInternalServerError)
-		return
+		return models.TaskResponse{}, false, &submitError{http.StatusInternalServerError, "Failed to create task"}
+	}
+
+	// Record the task in the by-time index so /tasks can page through
+	// history newest-first without scanning every url_task:* key.
+	if err := services.RecordTaskCreated(urlStr, createdAt); err != nil {
+		log.Printf("Failed to record task creation time: %v", err)
 	}
 
 	// 6. Create task payload and add to queue
 	taskPayload := models.TaskPayload{
-		URL:  req.URL,
-		UUID: taskUUID,
+		URL:       urlStr,
+		UUID:      taskUUID,
+		RequestID: requestID,
 	}
 
 	taskData, err := json.Marshal(taskPayload)
 	if err != nil {
 		log.Printf("Failed to marshal task payload: %v", err)
-		http.Error(w, "Failed to create task", http.StatusInternalServerError)
-		return
+		return models.TaskResponse{}, false, &submitError{http.StatusInternalServerError, "Failed to create task"}
 	}
 
 	// Push task to queue
 	if err := services.PushTaskToQueue(taskData); err != nil {
 		log.Printf("Failed to push to queue: %v", err)
-		http.Error(w, "Failed to queue task", http.StatusInternalServerError)
-		return
+		return models.TaskResponse{}, false, &submitError{http.StatusInternalServerError, "Failed to queue task"}
 	}
 
-	fmt.Printf("Task queued successfully: %s\n", string(taskData))
+	logging.WithFields(taskUUID, urlStr, "submit").WithField("request_id", requestID).Info("forwarded")
 
-	// 7. Return pending status with UUID
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(models.TaskResponse{
-		Status: "pending",
-		UUID:   taskUUID,
-	})
+	return models.TaskResponse{Status: "pending", UUID: taskUUID}, false, nil
 } 
\ No newline at end of file
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"backend/models"
+	"backend/services"
+)
+
+// HandleCancelTask handles DELETE /tasks/{uuid}, requesting cooperative
+// cancellation of a pending or in-progress task: it flags
+// task:{uuid}:cancel, moves the task's status to "cancelling" (publishing
+// that change on its task:{uuid}:events channel), and - with force=true -
+// removes the task from the pending queue if no worker has claimed it yet.
+//
+// Actually honoring the flag mid-pipeline is the scraper/LLM worker's
+// responsibility: it should poll services.IsTaskCancelled between stages
+// and watch ctx.Done() on its outbound calls, then mark the task
+// "cancelled" itself. That worker loop is a separate service outside this
+// Go backend, so there's nothing here to wire it into.
+func HandleCancelTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	taskUUID := extractCancelTaskUUID(r.URL.Path)
+	if taskUUID == "" {
+		http.Error(w, "Invalid UUID", http.StatusBadRequest)
+		return
+	}
+	if _, err := uuid.Parse(taskUUID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	status, err := services.GetTaskStatus(taskUUID)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	if status != "pending" && status != "in_progress" {
+		http.Error(w, "Only pending or in-progress tasks can be cancelled", http.StatusConflict)
+		return
+	}
+
+	if err := services.RequestTaskCancellation(taskUUID); err != nil {
+		log.Printf("Failed to set cancel flag for task %s: %v", taskUUID, err)
+		http.Error(w, "Failed to cancel task", http.StatusInternalServerError)
+		return
+	}
+
+	if err := services.SetTaskStatus(taskUUID, "cancelling"); err != nil {
+		log.Printf("Failed to update status for task %s: %v", taskUUID, err)
+		http.Error(w, "Failed to cancel task", http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("force") == "true" {
+		removed, err := services.CancelPendingTaskInQueue(taskUUID)
+		if err != nil {
+			log.Printf("Failed to force-remove task %s from queue: %v", taskUUID, err)
+		} else if removed {
+			if taskURL, err := services.FindURLByTaskUUID(taskUUID); err == nil {
+				if err := services.DeleteURLTaskMapping(taskURL); err != nil {
+					log.Printf("Failed to delete URL task mapping for %s: %v", taskURL, err)
+				}
+			}
+			if err := services.SetTaskStatus(taskUUID, "cancelled"); err != nil {
+				log.Printf("Failed to mark unclaimed task %s cancelled: %v", taskUUID, err)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.TaskResponse{
+		Status: "cancelling",
+		UUID:   taskUUID,
+	})
+}
+
+// extractCancelTaskUUID pulls {uuid} out of the /tasks/{uuid} path used by
+// DELETE requests, as opposed to the /tasks/{uuid}/stream SSE path.
+func extractCancelTaskUUID(path string) string {
+	const prefix = "/tasks/"
+	if !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+	uuidPart := strings.TrimPrefix(path, prefix)
+	if uuidPart == "" || strings.Contains(uuidPart, "/") {
+		return ""
+	}
+	return uuidPart
+}
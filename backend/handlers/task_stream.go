@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"backend/models"
+	"backend/services"
+)
+
+// keepaliveInterval is how often HandleTaskStream sends a comment line to
+// keep the connection (and any intermediate proxy) from timing it out.
+const keepaliveInterval = 15 * time.Second
+
+// isTerminalStatus reports whether status is a final state HandleTaskStream
+// should emit once and then close the connection for, rather than keep
+// waiting on further events.
+func isTerminalStatus(status string) bool {
+	return status == "done" || status == "error" || status == "cancelled"
+}
+
+// HandleTaskStream upgrades to text/event-stream and pushes task status
+// events (queued, scraping, summarizing, done, error) as they occur,
+// instead of requiring the client to poll /status/{uuid}.
+func HandleTaskStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	taskUUID := extractTaskStreamUUID(r.URL.Path)
+	if taskUUID == "" {
+		http.Error(w, "Invalid UUID", http.StatusBadRequest)
+		return
+	}
+	if _, err := uuid.Parse(taskUUID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// If the task is already done (or errored) when the stream opens,
+	// emit the final event immediately and close instead of waiting on a
+	// pub/sub message that already happened.
+	if status, err := services.GetTaskStatus(taskUUID); err == nil && isTerminalStatus(status) {
+		writeTaskEvent(w, flusher, taskUUID, status)
+		return
+	}
+
+	sub := services.SubscribeTaskEvents(taskUUID)
+	defer sub.Close()
+	ch := sub.Channel()
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", msg.Payload)
+			flusher.Flush()
+
+			var event models.TaskEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err == nil && isTerminalStatus(event.Event) {
+				return
+			}
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeTaskEvent writes a single event frame and flushes it.
+func writeTaskEvent(w http.ResponseWriter, flusher http.Flusher, taskUUID, status string) {
+	event := models.TaskEvent{UUID: taskUUID, Event: status}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "data: %s\n\n", payload)
+	flusher.Flush()
+}
+
+// extractTaskStreamUUID pulls {uuid} out of the /tasks/{uuid}/stream path.
+func extractTaskStreamUUID(path string) string {
+	const prefix, suffix = "/tasks/", "/stream"
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, suffix) {
+		return ""
+	}
+	uuidPart := strings.TrimSuffix(strings.TrimPrefix(path, prefix), suffix)
+	if uuidPart == "" || strings.Contains(uuidPart, "/") {
+		return ""
+	}
+	return uuidPart
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Log is the package-wide structured logger. JSON output keeps log lines
+// machine-parseable once they're shipped off-box (log aggregator, etc.).
+var Log = logrus.New()
+
+func init() {
+	Log.SetFormatter(&logrus.JSONFormatter{})
+	Log.SetOutput(os.Stdout)
+
+	level, err := logrus.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	Log.SetLevel(level)
+}
+
+// WithFields returns a logger entry tagged with the task UUID, article URL
+// and component name so logs for a single task can be filtered and traced
+// end to end across the Go backend, LLM server, and db-service.
+func WithFields(uuid, url, component string) *logrus.Entry {
+	return Log.WithFields(logrus.Fields{
+		"uuid":      uuid,
+		"url":       url,
+		"component": component,
+	})
+}
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID returns a copy of ctx carrying requestID for later
+// retrieval via RequestIDFromContext.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext extracts the request ID stashed by
+// middleware.RequestID, returning "" if none is present.
+func RequestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
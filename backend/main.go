@@ -19,21 +19,36 @@ func main() {
 	hub := websocket.NewHub()
 	go hub.Run()
 
-	// Start the Redis pub/sub subscriber
+	// Start the Redis pub/sub subscribers
 	go services.StartResultSubscriber(hub)
+	go services.StartWSSyncSubscriber(hub)
 
-	// Apply CORS middleware to all routes
-	http.HandleFunc("/submit", middleware.CORS(handlers.HandleSubmit))
-	http.HandleFunc("/status/", middleware.CORS(handlers.HandleStatus))
-	http.HandleFunc("/tasks", middleware.CORS(handlers.HandleTasks))
-	http.HandleFunc("/conversation/update", middleware.CORS(handlers.HandleConversationUpdate))
-	http.HandleFunc("/inform-model-loaded", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
-		handlers.HandleModelLoaded(w, r, hub)
+	// Periodically rebuild any missing uuid_url index entries in the
+	// background instead of reconciling inline on every lookup miss
+	go services.StartUUIDIndexReconciler(services.UUIDIndexReconcileInterval)
+
+	// Apply CORS middleware to all routes, and request-ID middleware to all
+	// routes except /tasks/ and /ws
+	http.HandleFunc("/submit", middleware.CORS(middleware.RequestID(middleware.Idempotency(middleware.IPRateLimit(middleware.URLRateLimit(handlers.HandleSubmit))))))
+	http.HandleFunc("/submit/bulk", middleware.CORS(middleware.RequestID(middleware.Idempotency(middleware.IPRateLimit(handlers.HandleBulkSubmit)))))
+	http.HandleFunc("/status/", middleware.CORS(middleware.RequestID(handlers.HandleStatus)))
+	http.HandleFunc("/tasks", middleware.CORS(middleware.RequestID(handlers.HandleTasks)))
+	http.HandleFunc("/tasks/", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			handlers.HandleCancelTask(w, r)
+			return
+		}
+		handlers.HandleTaskStream(w, r)
 	}))
-	http.HandleFunc("/is-model-loaded", middleware.CORS(handlers.HandleIsModelLoaded))
-	http.HandleFunc("/chat", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/groups/", middleware.CORS(middleware.RequestID(handlers.HandleGroupStatus)))
+	http.HandleFunc("/conversation/update", middleware.CORS(middleware.RequestID(middleware.Idempotency(middleware.IPRateLimit(handlers.HandleConversationUpdate)))))
+	http.HandleFunc("/inform-model-loaded", middleware.CORS(middleware.RequestID(func(w http.ResponseWriter, r *http.Request) {
+		handlers.HandleModelLoaded(w, r, hub)
+	})))
+	http.HandleFunc("/is-model-loaded", middleware.CORS(middleware.RequestID(handlers.HandleIsModelLoaded)))
+	http.HandleFunc("/chat", middleware.CORS(middleware.RequestID(func(w http.ResponseWriter, r *http.Request) {
 		handlers.HandleChat(w, r, hub)
-	}))
+	})))
 	http.HandleFunc("/ws", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
 		handlers.HandleWebSocket(w, r, hub)
 	}))
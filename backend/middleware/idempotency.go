@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"time"
+
+	"backend/services"
+)
+
+// IdempotencyKeyHeader is the header clients set to make a request safely
+// retryable.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const idempotencyTTL = 10 * time.Minute
+
+// Idempotency recognizes an Idempotency-Key header, storing the response
+// body under idem:{key} with a TTL so retries return the cached response
+// instead of re-running the handler (and, for /submit, enqueuing a
+// duplicate LLM job).
+func Idempotency(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		cacheKey := "idem:" + key
+		if cached, err := services.GetRedisClient().Get(services.GetContext(), cacheKey).Result(); err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(cached))
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next(recorder, r)
+
+		if recorder.statusCode >= 200 && recorder.statusCode < 300 {
+			if err := services.GetRedisClient().Set(services.GetContext(), cacheKey, recorder.body.Bytes(), idempotencyTTL).Err(); err != nil {
+				log.Printf("Failed to cache idempotent response for key %s: %v", key, err)
+			}
+		}
+	}
+}
+
+// responseRecorder captures the response status and body as the wrapped
+// handler writes them, so Idempotency can cache them afterwards.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	rr.statusCode = statusCode
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"backend/services"
+)
+
+const (
+	rateLimitWindow = 1 * time.Minute
+	perIPLimit      = 60 // requests per IP per window
+	perURLLimit     = 10 // requests per URL per window
+)
+
+// incrCounter increments a Redis INCR counter for key, setting its TTL to
+// window on the first increment, and reports whether it has exceeded
+// limit. This is a simple fixed-window token-bucket approximation backed
+// by Redis rather than in-process state, so it works across instances.
+func incrCounter(key string, limit int, window time.Duration) (exceeded bool, err error) {
+	rdb := services.GetRedisClient()
+	ctx := services.GetContext()
+
+	count, err := rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if count == 1 {
+		rdb.Expire(ctx, key, window)
+	}
+
+	return count > int64(limit), nil
+}
+
+// clientIP extracts the request's source IP, falling back to the raw
+// RemoteAddr if it isn't in host:port form.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// IPRateLimit enforces a per-IP request limit backed by Redis INCR+EXPIRE
+// counters.
+func IPRateLimit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r)
+
+		exceeded, err := incrCounter("ratelimit:ip:"+ip, perIPLimit, rateLimitWindow)
+		if err != nil {
+			log.Printf("Rate limit check failed for IP %s: %v", ip, err)
+		} else if exceeded {
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// URLRateLimit enforces a per-URL request limit for handlers whose JSON
+// body contains a "url" field (e.g. /submit). It peeks at the body to
+// extract the URL, then restores it so the wrapped handler can still
+// decode the full request.
+func URLRateLimit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var payload struct {
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal(body, &payload); err == nil && payload.URL != "" {
+			exceeded, err := incrCounter("ratelimit:url:"+payload.URL, perURLLimit, rateLimitWindow)
+			if err != nil {
+				log.Printf("Rate limit check failed for URL %s: %v", payload.URL, err)
+			} else if exceeded {
+				http.Error(w, "Too many requests for this URL", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"backend/logging"
+)
+
+// RequestIDHeader is the header a caller can supply to propagate its own
+// request ID, and that the response echoes back.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID assigns a request ID (or propagates one supplied by the
+// caller), injects it into the request context, and stamps it onto the
+// response so a single task can be traced end to end across logs.
+func RequestID(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		ctx := logging.WithRequestID(r.Context(), requestID)
+		next(w, r.WithContext(ctx))
+	}
+}
@@ -13,6 +13,13 @@ type TaskUpdateMessage struct {
 	Result string `json:"result,omitempty"`
 }
 
+// TaskEvent is published on task:{uuid}:events whenever a task's status
+// changes, and streamed to clients via HandleTaskStream.
+type TaskEvent struct {
+	UUID  string `json:"uuid"`
+	Event string `json:"event"` // queued, scraping, summarizing, done, error
+}
+
 type ArticleRequest struct {
 	URL string `json:"url"`
 }
@@ -26,29 +33,81 @@ type TaskResponse struct {
 type TaskPayload struct {
 	URL  string `json:"url"`
 	UUID string `json:"uuid"`
+	// RequestID carries the originating HTTP request's X-Request-ID through
+	// the queue so a worker that echoes it back on ProcessResult lets
+	// StartResultSubscriber tag the async half of a task's lifecycle with
+	// the same ID as the synchronous half.
+	RequestID string `json:"request_id,omitempty"`
 }
 
-type URLTaskMapping struct {
-	UUID   string `json:"uuid"`
+// BulkSubmitRequest is the body for POST /submit/bulk.
+type BulkSubmitRequest struct {
+	URLs      []string `json:"urls"`
+	GroupName string   `json:"group_name,omitempty"`
+}
+
+// BulkSubmitItemResult reports how one URL within a bulk submission fared.
+type BulkSubmitItemResult struct {
+	URL    string `json:"url"`
 	Status string `json:"status"`
+	UUID   string `json:"uuid,omitempty"`
+	Cached bool   `json:"cached"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkSubmitResponse is returned by POST /submit/bulk.
+type BulkSubmitResponse struct {
+	GroupUUID string                 `json:"group_uuid"`
+	Results   []BulkSubmitItemResult `json:"results"`
+}
+
+// TaskGroup records which task UUIDs were submitted together as a bulk
+// batch, so GET /groups/{group_uuid} can aggregate their statuses later.
+type TaskGroup struct {
+	GroupUUID string   `json:"group_uuid"`
+	GroupName string   `json:"group_name,omitempty"`
+	TaskUUIDs []string `json:"task_uuids"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+// GroupStatusResponse is returned by GET /groups/{group_uuid}.
+type GroupStatusResponse struct {
+	GroupUUID string         `json:"group_uuid"`
+	GroupName string         `json:"group_name,omitempty"`
+	Tasks     []TaskResponse `json:"tasks"`
+}
+
+type URLTaskMapping struct {
+	UUID      string `json:"uuid"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at,omitempty"` // unix millis
 }
 
 type ProcessResult struct {
-	UUID   string                 `json:"uuid"`
-	URL    string                 `json:"url"`
-	Result map[string]interface{} `json:"result"`
+	UUID string `json:"uuid"`
+	URL  string `json:"url"`
+	// RequestID is the TaskPayload.RequestID the worker echoed back, if
+	// any. Older workers that don't round-trip it leave this empty, so
+	// StartResultSubscriber's logs fall back to UUID-only correlation.
+	RequestID string                 `json:"request_id,omitempty"`
+	Result    map[string]interface{} `json:"result"`
 }
 
 type TaskHistoryItem struct {
-	URL      string `json:"url"`
-	UUID     string `json:"uuid"`
-	Status   string `json:"status"`
-	Summary  string `json:"summary,omitempty"`
-	Sentiment string `json:"sentiment,omitempty"`
+	URL          string              `json:"url"`
+	UUID         string              `json:"uuid"`
+	Status       string              `json:"status"`
+	CreatedAt    int64               `json:"created_at,omitempty"`
+	Summary      string              `json:"summary,omitempty"`
+	Sentiment    string              `json:"sentiment,omitempty"`
+	Conversation []ConversationEntry `json:"conversation,omitempty"`
 }
 
 type TaskHistoryResponse struct {
-	Tasks []TaskHistoryItem `json:"tasks"`
+	Tasks    []TaskHistoryItem `json:"tasks"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
 }
 
 // ArticleResultPayload matches the db-service model
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrDBUnavailable is returned when the circuit breaker is open and a call
+// is short-circuited without ever reaching db-service.
+var ErrDBUnavailable = errors.New("db-service unavailable (circuit open)")
+
+const (
+	dbRequestTimeout        = 5 * time.Second
+	dbMaxRetries            = 3
+	dbRetryBaseDelay        = 100 * time.Millisecond
+	dbBreakerFailThreshold  = 5
+	dbBreakerCooldown       = 30 * time.Second
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// DBClient wraps HTTP calls to db-service with a timeout, retry-with-jitter
+// on network errors and 5xx responses, and a circuit breaker so a
+// struggling db-service doesn't pin goroutines forever or cascade into
+// every request handler.
+type DBClient struct {
+	httpClient    *http.Client
+	baseURL       string
+	failThreshold int
+	cooldown      time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// NewDBClient builds a DBClient pointed at baseURL, e.g. "http://db-service:5000".
+func NewDBClient(baseURL string) *DBClient {
+	return &DBClient{
+		httpClient:    &http.Client{Timeout: dbRequestTimeout},
+		baseURL:       baseURL,
+		failThreshold: dbBreakerFailThreshold,
+		cooldown:      dbBreakerCooldown,
+	}
+}
+
+// defaultDBClient is the client used by the package-level GetArticleFromDBService
+// and friends, matching db-service's address in the rest of this codebase.
+var defaultDBClient = NewDBClient("http://db-service:5000")
+
+// allowRequest reports whether the breaker currently lets a call through,
+// moving an open breaker to half-open once the cooldown has elapsed.
+func (c *DBClient) allowRequest() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == breakerOpen {
+		if time.Since(c.openedAt) < c.cooldown {
+			return false
+		}
+		c.state = breakerHalfOpen
+	}
+	return true
+}
+
+// recordSuccess closes the breaker and resets its failure count.
+func (c *DBClient) recordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = breakerClosed
+	c.failures = 0
+}
+
+// recordFailure counts a failed attempt, opening the breaker once
+// dbBreakerFailThreshold consecutive failures accumulate (a failed
+// half-open probe re-opens it immediately).
+func (c *DBClient) recordFailure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == breakerHalfOpen {
+		c.state = breakerOpen
+		c.openedAt = time.Now()
+		return
+	}
+
+	c.failures++
+	if c.failures >= c.failThreshold {
+		c.state = breakerOpen
+		c.openedAt = time.Now()
+	}
+}
+
+// do sends method/path (+body) to db-service, making up to dbMaxRetries
+// attempts in total with jittered exponential backoff between them on
+// network errors or 5xx responses. The breaker is checked before the first attempt and updated
+// from the final outcome.
+func (c *DBClient) do(ctx context.Context, method, path string, body []byte) ([]byte, int, error) {
+	if !c.allowRequest() {
+		return nil, 0, ErrDBUnavailable
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < dbMaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := dbRetryBaseDelay * time.Duration(1<<uint(attempt-1))
+			jitter := time.Duration(rand.Int63n(int64(backoff)))
+			select {
+			case <-time.After(backoff + jitter):
+			case <-ctx.Done():
+				return nil, 0, ctx.Err()
+			}
+		}
+
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+		if err != nil {
+			return nil, 0, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				c.recordFailure()
+				return nil, 0, err
+			}
+			continue
+		}
+
+		respBody, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			lastErr = readErr
+			continue
+		}
+
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("db-service returned %d", resp.StatusCode)
+			continue
+		}
+
+		c.recordSuccess()
+		return respBody, resp.StatusCode, nil
+	}
+
+	c.recordFailure()
+	return nil, 0, fmt.Errorf("db-service request failed after %d attempts: %w", dbMaxRetries, lastErr)
+}
+
+// GetArticle queries db-service for an article by URL.
+func (c *DBClient) GetArticle(ctx context.Context, url string) (string, error) {
+	body, status, err := c.do(ctx, http.MethodGet, "/article?url="+url, nil)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("not found")
+	}
+	return string(body), nil
+}
+
+// SaveArticle saves an ArticleResultPayload JSON document to db-service.
+func (c *DBClient) SaveArticle(ctx context.Context, articleJSON string) error {
+	_, status, err := c.do(ctx, http.MethodPost, "/article", []byte(articleJSON))
+	if err != nil {
+		return err
+	}
+	if status != http.StatusCreated {
+		return fmt.Errorf("failed to save article: %d", status)
+	}
+	return nil
+}
+
+// UpdateConversation updates the stored conversation for uuid in db-service.
+func (c *DBClient) UpdateConversation(ctx context.Context, uuid string, conversationJSON string) error {
+	_, status, err := c.do(ctx, http.MethodPut, "/article/conversation", []byte(conversationJSON))
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to update conversation: %d", status)
+	}
+	return nil
+}
+
+// GetArticleByUUID queries db-service for an article by UUID.
+func (c *DBClient) GetArticleByUUID(ctx context.Context, uuid string) (string, error) {
+	body, status, err := c.do(ctx, http.MethodGet, "/article/uuid/"+uuid, nil)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("not found")
+	}
+	return string(body), nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestDBClient(server *httptest.Server) *DBClient {
+	return &DBClient{
+		httpClient:    server.Client(),
+		baseURL:       server.URL,
+		failThreshold: 3,
+		cooldown:      50 * time.Millisecond,
+	}
+}
+
+func TestDBClientRetriesOn5xxThenSucceeds(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"summary":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := newTestDBClient(server)
+	result, err := client.GetArticle(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("expected eventual success, got error: %v", err)
+	}
+	if result != `{"summary":"ok"}` {
+		t.Fatalf("unexpected body: %q", result)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestDBClientGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := newTestDBClient(server)
+	if _, err := client.GetArticle(context.Background(), "https://example.com"); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if got := atomic.LoadInt32(&calls); got != dbMaxRetries {
+		t.Fatalf("expected %d attempts, got %d", dbMaxRetries, got)
+	}
+}
+
+func TestDBClientRespectsContextTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestDBClient(server)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetArticle(ctx, "https://example.com"); err == nil {
+		t.Fatal("expected context deadline error")
+	}
+}
+
+func TestDBClientBreakerOpensAndRecovers(t *testing.T) {
+	var calls int32
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"summary":"recovered"}`))
+	}))
+	defer server.Close()
+
+	client := newTestDBClient(server)
+
+	// Each call below exhausts its own retries and fails, accumulating one
+	// breaker failure per call until the breaker opens.
+	for i := 0; i < client.failThreshold; i++ {
+		if _, err := client.GetArticle(context.Background(), "https://example.com"); err == nil {
+			t.Fatalf("call %d: expected failure while server is down", i)
+		}
+	}
+
+	callsBeforeOpen := atomic.LoadInt32(&calls)
+	if _, err := client.GetArticle(context.Background(), "https://example.com"); err != ErrDBUnavailable {
+		t.Fatalf("expected ErrDBUnavailable once breaker is open, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != callsBeforeOpen {
+		t.Fatal("breaker should have short-circuited without contacting the server")
+	}
+
+	// Let the cooldown elapse and the server recover; the next call should
+	// be let through as a half-open probe and succeed, closing the breaker.
+	time.Sleep(client.cooldown + 10*time.Millisecond)
+	failing.Store(false)
+
+	result, err := client.GetArticle(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("expected half-open probe to succeed, got: %v", err)
+	}
+	if result != `{"summary":"recovered"}` {
+		t.Fatalf("unexpected body: %q", result)
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"backend/models"
+)
+
+// SaveGroup persists a bulk submission's task grouping so GET
+// /groups/{group_uuid} can aggregate their statuses later.
+func SaveGroup(group models.TaskGroup) error {
+	data, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+	return rdb.Set(ctx, "group:"+group.GroupUUID, data, 0).Err()
+}
+
+// GetGroup loads a previously saved task group by its group UUID.
+func GetGroup(groupUUID string) (*models.TaskGroup, error) {
+	data, err := rdb.Get(ctx, "group:"+groupUUID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("group not found: %s", groupUUID)
+	}
+
+	var group models.TaskGroup
+	if err := json.Unmarshal([]byte(data), &group); err != nil {
+		return nil, fmt.Errorf("failed to parse group %s: %v", groupUUID, err)
+	}
+	return &group, nil
+}
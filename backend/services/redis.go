@@ -8,68 +8,126 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"backend/logging"
 	"backend/models"
 	"backend/websocket"
 )
 
 var ctx = context.Background()
-var rdb *redis.Client
+var rdb redis.UniversalClient
 
-// InitRedis initializes the Redis client
+// InitRedis initializes the Redis client from RedisConfig, picking a
+// standalone, Sentinel (FailoverClient) or Cluster client depending on
+// cfg.Mode. Falls back to the previous hardcoded single-node behavior if
+// the config cannot be loaded.
 func InitRedis() {
-	rdb = redis.NewClient(&redis.Options{
-		Addr: "redis:6379", // container name
-	})
+	cfg, err := LoadRedisConfig()
+	if err != nil {
+		log.Printf("Failed to load Redis config, falling back to defaults: %v", err)
+		cfg = defaultRedisConfig()
+	}
+	rdb = newRedisClient(cfg)
+}
+
+// newRedisClient builds the appropriate go-redis client for cfg.Mode.
+func newRedisClient(cfg *RedisConfig) redis.UniversalClient {
+	switch cfg.Mode {
+	case RedisModeSentinel:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    cfg.MasterName,
+			SentinelAddrs: cfg.Addrs,
+			Username:      cfg.Username,
+			Password:      cfg.Password,
+			DB:            cfg.DB,
+			TLSConfig:     cfg.tlsConfig(),
+			PoolSize:      cfg.PoolSize,
+			MinIdleConns:  cfg.MinIdleConns,
+			DialTimeout:   cfg.DialTimeout,
+			ReadTimeout:   cfg.ReadTimeout,
+			WriteTimeout:  cfg.WriteTimeout,
+		})
+	case RedisModeCluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			TLSConfig:    cfg.tlsConfig(),
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		})
+	default:
+		addr := "redis:6379"
+		if len(cfg.Addrs) > 0 {
+			addr = cfg.Addrs[0]
+		}
+		return redis.NewClient(&redis.Options{
+			Addr:         addr,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			TLSConfig:    cfg.tlsConfig(),
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		})
+	}
 }
 
 // GetRedisClient returns the Redis client
-func GetRedisClient() *redis.Client {
+func GetRedisClient() redis.UniversalClient {
 	return rdb
 }
 
-// StartResultSubscriber starts the Redis pub/sub subscriber for process results
+// StartResultSubscriber starts the Redis pub/sub subscriber for process
+// results, emitting received/broadcast/cached lifecycle events tagged
+// with the task UUID. The request ID is included too when the worker
+// echoed TaskPayload.RequestID back on ProcessResult; workers that don't
+// round-trip it leave the logged request_id empty, falling back to
+// UUID-only correlation for the async half of a task's lifecycle.
 func StartResultSubscriber(hub *websocket.Hub) {
-	fmt.Println("Starting Redis pub/sub subscriber for process:results...")
-	
+	logging.WithFields("", "", "result_subscriber").Info("starting Redis pub/sub subscriber for process:results")
+
 	pubsub := rdb.Subscribe(ctx, "process:results")
 	defer pubsub.Close()
-	
+
 	ch := pubsub.Channel()
-	
+
 	for msg := range ch {
-		fmt.Printf("Received result from LLM server: %s\n", msg.Payload)
-		
 		// Parse the structured result
 		var result models.ProcessResult
 		if err := json.Unmarshal([]byte(msg.Payload), &result); err != nil {
 			log.Printf("Failed to parse result message: %v", err)
 			continue
 		}
-		
-		fmt.Printf("Task %s completed for URL: %s\n", result.UUID, result.URL)
-		fmt.Printf("Result summary: %v\n", result.Result["summary"])
-		
+
+		logFields := logging.WithFields(result.UUID, result.URL, "result_subscriber").WithField("request_id", result.RequestID)
+		logFields.Info("received")
+
 		// Broadcast the result to all WebSocket clients
 		taskUpdate := models.TaskUpdateMessage{
 			UUID:   result.UUID,
 			URL:    result.URL,
 			Status: "done",
 		}
-		fmt.Printf("Task update: %v\n", taskUpdate)
-		
+
 		// Convert result to JSON string for the message
 		if resultStr, err := json.Marshal(result.Result); err == nil {
 			taskUpdate.Result = string(resultStr)
 		}
-		
+
 		wsMessage := models.WSMessage{
 			Type:    "task_update",
 			Payload: taskUpdate,
 		}
-		
+
 		if messageBytes, err := json.Marshal(wsMessage); err == nil {
-			hub.GetBroadcastChannel() <- messageBytes
-			fmt.Printf("Broadcasted task update to WebSocket clients: %s\n", string(messageBytes))
+			BroadcastWS(hub, messageBytes)
+			logFields.Info("broadcast")
 		} else {
 			log.Printf("Failed to marshal WebSocket message: %v", err)
 		}
@@ -82,9 +140,7 @@ func StartResultSubscriber(hub *websocket.Hub) {
 		if s, ok := result.Result["sentiment"].(string); ok {
 			sentiment = s
 		}
-		
-		fmt.Printf("UUID from result: %s\n", result.UUID)
-		
+
 		articlePayload := models.ArticleResultPayload{
 			UUID:         result.UUID,
 			URL:          result.URL,
@@ -94,13 +150,17 @@ func StartResultSubscriber(hub *websocket.Hub) {
 		}
 		resultJSON, err := json.Marshal(articlePayload)
 		if err == nil {
-			fmt.Printf("Sending to db-service: %s\n", string(resultJSON))
-			SaveArticleToDBService(string(resultJSON))
+			if err := SaveArticleToDBService(ctx, string(resultJSON)); err != nil {
+				log.Printf("Failed to save article to db-service: %v", err)
+			}
 		} else {
-			fmt.Printf("Failed to marshal article payload: %v\n", err)
+			log.Printf("Failed to marshal article payload: %v", err)
 		}
 		// 2. Save to Redis with TTL (cache the original result as before)
 		rdb.Set(ctx, "cache:"+result.URL, msg.Payload, 1*time.Minute)
+		// 3. Keep the uuid_url secondary index in sync with the same TTL
+		rdb.Set(ctx, "uuid_url:"+result.UUID, result.URL, 1*time.Minute)
+		logFields.Info("cached")
 	}
 }
 
@@ -128,10 +188,18 @@ func SetURLTaskMapping(url string, mappingData []byte) error {
 	return rdb.Set(ctx, urlTaskKey, mappingData, 0).Err()
 }
 
-// SetTaskStatus sets the status for a task UUID
+// SetTaskStatus sets the status for a task UUID and publishes the change
+// on its task:{uuid}:events channel so HandleTaskStream can push it to
+// subscribed clients without polling.
 func SetTaskStatus(taskUUID string, status string) error {
 	statusKey := "status:" + taskUUID
-	return rdb.Set(ctx, statusKey, status, 0).Err()
+	if err := rdb.Set(ctx, statusKey, status, 0).Err(); err != nil {
+		return err
+	}
+	if err := PublishTaskEvent(taskUUID, status); err != nil {
+		log.Printf("Failed to publish task event for %s: %v", taskUUID, err)
+	}
+	return nil
 }
 
 // GetTaskStatus gets the status for a task UUID
@@ -145,9 +213,30 @@ func PushTaskToQueue(taskData []byte) error {
 	return rdb.LPush(ctx, "queue:tasks", taskData).Err()
 }
 
-// GetAllURLTaskKeys gets all URL task mapping keys
+// GetAllURLTaskKeys gets all URL task mapping keys. Uses SCAN rather than
+// KEYS so a large keyspace doesn't block Redis while this runs.
 func GetAllURLTaskKeys() ([]string, error) {
-	return rdb.Keys(ctx, "url_task:*").Result()
+	return scanKeys("url_task:*")
+}
+
+// scanKeys collects every key matching pattern using SCAN with a cursor,
+// which walks the keyspace incrementally instead of blocking Redis the way
+// KEYS does.
+func scanKeys(pattern string) ([]string, error) {
+	var keys []string
+	var cursor uint64
+	for {
+		batch, nextCursor, err := rdb.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+		cursor = nextCursor
+		if cursor == 0 {
+			break
+		}
+	}
+	return keys, nil
 }
 
 // GetContext returns the Redis context
@@ -155,81 +244,75 @@ func GetContext() context.Context {
 	return ctx
 }
 
-// UpdateConversationInCache updates the conversation for an article in the cache
+// UpdateConversationInCache updates the conversation for an article in the
+// cache, looking the URL up via the uuid_url secondary index instead of
+// scanning every cache:* entry.
 func UpdateConversationInCache(uuid string, conversation []models.ConversationEntry) error {
-	// First, we need to find the URL for this UUID by checking all cached items
-	// This is a bit inefficient but necessary since we cache by URL, not UUID
-	cacheKeys, err := rdb.Keys(ctx, "cache:*").Result()
+	url, cacheKey, cachedData, err := lookupCacheByUUID(uuid)
 	if err != nil {
-		return fmt.Errorf("failed to get cache keys: %v", err)
+		return err
 	}
-	
-	for _, cacheKey := range cacheKeys {
-		cachedData, err := rdb.Get(ctx, cacheKey).Result()
-		if err != nil {
-			continue
-		}
-		
-		// Try to parse the cached data to check if it matches our UUID
-		var result models.ProcessResult
-		if err := json.Unmarshal([]byte(cachedData), &result); err != nil {
-			continue
-		}
-		
-		if result.UUID == uuid {
-			// Found the matching cached item, update the conversation
-			if result.Result == nil {
-				result.Result = make(map[string]interface{})
-			}
-			result.Result["conversation"] = conversation
-			
-			// Marshal the updated result
-			updatedData, err := json.Marshal(result)
-			if err != nil {
-				return fmt.Errorf("failed to marshal updated result: %v", err)
-			}
-			
-			// Update the cache with the new data (preserve TTL)
-			ttl, err := rdb.TTL(ctx, cacheKey).Result()
-			if err != nil {
-				ttl = 1 * time.Minute // Default TTL if we can't get it
-			}
-			
-			err = rdb.Set(ctx, cacheKey, updatedData, ttl).Err()
-			if err != nil {
-				return fmt.Errorf("failed to update cache: %v", err)
-			}
-			
-			fmt.Printf("Updated conversation in cache for UUID: %s, URL: %s\n", uuid, result.URL)
-			return nil
-		}
+
+	var result models.ProcessResult
+	if err := json.Unmarshal([]byte(cachedData), &result); err != nil {
+		return fmt.Errorf("failed to parse cached result for URL %s: %v", url, err)
+	}
+
+	if result.Result == nil {
+		result.Result = make(map[string]interface{})
+	}
+	result.Result["conversation"] = conversation
+
+	updatedData, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal updated result: %v", err)
+	}
+
+	// Preserve whatever TTL the entry already had.
+	ttl, err := rdb.TTL(ctx, cacheKey).Result()
+	if err != nil || ttl <= 0 {
+		ttl = 1 * time.Minute // Default TTL if we can't get it
+	}
+
+	if err := rdb.Set(ctx, cacheKey, updatedData, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to update cache: %v", err)
 	}
-	
-	return fmt.Errorf("no cached article found for UUID: %s", uuid)
+
+	logging.WithFields(uuid, url, "conversation").Info("updated_in_cache")
+	return nil
 }
 
-// GetArticleByUUIDFromCache retrieves an article from cache by UUID
+// GetArticleByUUIDFromCache retrieves an article from cache by UUID via the
+// uuid_url secondary index instead of scanning every cache:* entry.
 func GetArticleByUUIDFromCache(uuid string) (*models.ProcessResult, error) {
-	cacheKeys, err := rdb.Keys(ctx, "cache:*").Result()
+	_, _, cachedData, err := lookupCacheByUUID(uuid)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cache keys: %v", err)
+		return nil, err
 	}
-	
-	for _, cacheKey := range cacheKeys {
-		cachedData, err := rdb.Get(ctx, cacheKey).Result()
-		if err != nil {
-			continue
-		}
-		
-		var result models.ProcessResult
-		if err := json.Unmarshal([]byte(cachedData), &result); err != nil {
-			continue
-		}
-		
-		if result.UUID == uuid {
-			return &result, nil
-		}
+
+	var result models.ProcessResult
+	if err := json.Unmarshal([]byte(cachedData), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse cached result for UUID %s: %v", uuid, err)
 	}
-	
-	return nil, fmt.Errorf("no cached article found for UUID: %s", uuid)
+
+	return &result, nil
+}
+
+// lookupCacheByUUID resolves uuid -> url via uuid_url:{uuid}, then fetches
+// cache:{url}. A missing index entry is treated as a plain miss;
+// StartUUIDIndexReconciler keeps the index itself up to date in the
+// background so this never has to fall back to scanning cache:* inline.
+func lookupCacheByUUID(uuid string) (url string, cacheKey string, cachedData string, err error) {
+	url, err = rdb.Get(ctx, "uuid_url:"+uuid).Result()
+	if err != nil {
+		return "", "", "", fmt.Errorf("no cached article found for UUID: %s", uuid)
+	}
+
+	cacheKey = "cache:" + url
+	cachedData, err = rdb.Get(ctx, cacheKey).Result()
+	if err != nil {
+		return "", "", "", fmt.Errorf("no cached article found for UUID: %s", uuid)
+	}
+
+	return url, cacheKey, cachedData, nil
 } 
\ No newline at end of file
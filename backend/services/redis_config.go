@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RedisMode selects which go-redis client topology InitRedis builds.
+type RedisMode string
+
+const (
+	RedisModeStandalone RedisMode = "standalone"
+	RedisModeSentinel   RedisMode = "sentinel"
+	RedisModeCluster    RedisMode = "cluster"
+)
+
+// RedisConfig describes how to connect to Redis, covering standalone,
+// Sentinel (HA failover) and Cluster deployments behind a single shape so
+// operators can point the backend at managed Redis (ElastiCache, Upstash)
+// or a self-hosted Sentinel setup without recompiling.
+type RedisConfig struct {
+	Mode       RedisMode `json:"mode"`
+	Addrs      []string  `json:"addrs"`
+	MasterName string    `json:"master_name,omitempty"` // required for RedisModeSentinel
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	DB       int    `json:"db,omitempty"`
+
+	TLSEnabled            bool `json:"tls_enabled,omitempty"`
+	TLSInsecureSkipVerify bool `json:"tls_insecure_skip_verify,omitempty"`
+
+	PoolSize     int           `json:"pool_size,omitempty"`
+	MinIdleConns int           `json:"min_idle_conns,omitempty"`
+	DialTimeout  time.Duration `json:"dial_timeout,omitempty"`
+	ReadTimeout  time.Duration `json:"read_timeout,omitempty"`
+	WriteTimeout time.Duration `json:"write_timeout,omitempty"`
+}
+
+// defaultRedisConfig preserves the previous hardcoded single-node behavior.
+func defaultRedisConfig() *RedisConfig {
+	return &RedisConfig{
+		Mode:  RedisModeStandalone,
+		Addrs: []string{"redis:6379"}, // container name
+	}
+}
+
+// LoadRedisConfig builds a RedisConfig for InitRedis. If REDIS_CONFIG_FILE
+// is set, the config is read from that JSON file; otherwise it is assembled
+// from environment variables, falling back to defaultRedisConfig when none
+// are set.
+func LoadRedisConfig() (*RedisConfig, error) {
+	if path := os.Getenv("REDIS_CONFIG_FILE"); path != "" {
+		return loadRedisConfigFromFile(path)
+	}
+	return loadRedisConfigFromEnv(), nil
+}
+
+func loadRedisConfigFromFile(path string) (*RedisConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redis config file %s: %v", path, err)
+	}
+	cfg := defaultRedisConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse redis config file %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+func loadRedisConfigFromEnv() *RedisConfig {
+	cfg := defaultRedisConfig()
+
+	if mode := os.Getenv("REDIS_MODE"); mode != "" {
+		cfg.Mode = RedisMode(mode)
+	}
+	if addrs := os.Getenv("REDIS_ADDRS"); addrs != "" {
+		cfg.Addrs = splitAndTrim(addrs)
+	}
+	if masterName := os.Getenv("REDIS_MASTER_NAME"); masterName != "" {
+		cfg.MasterName = masterName
+	}
+	if username := os.Getenv("REDIS_USERNAME"); username != "" {
+		cfg.Username = username
+	}
+	if password := os.Getenv("REDIS_PASSWORD"); password != "" {
+		cfg.Password = password
+	}
+	if db, ok := envInt("REDIS_DB"); ok {
+		cfg.DB = db
+	}
+	if tlsEnabled, ok := envBool("REDIS_TLS_ENABLED"); ok {
+		cfg.TLSEnabled = tlsEnabled
+	}
+	if skipVerify, ok := envBool("REDIS_TLS_INSECURE_SKIP_VERIFY"); ok {
+		cfg.TLSInsecureSkipVerify = skipVerify
+	}
+	if poolSize, ok := envInt("REDIS_POOL_SIZE"); ok {
+		cfg.PoolSize = poolSize
+	}
+	if minIdleConns, ok := envInt("REDIS_MIN_IDLE_CONNS"); ok {
+		cfg.MinIdleConns = minIdleConns
+	}
+	if dialTimeout, ok := envDuration("REDIS_DIAL_TIMEOUT"); ok {
+		cfg.DialTimeout = dialTimeout
+	}
+	if readTimeout, ok := envDuration("REDIS_READ_TIMEOUT"); ok {
+		cfg.ReadTimeout = readTimeout
+	}
+	if writeTimeout, ok := envDuration("REDIS_WRITE_TIMEOUT"); ok {
+		cfg.WriteTimeout = writeTimeout
+	}
+
+	return cfg
+}
+
+// tlsConfig returns the *tls.Config to use for this RedisConfig, or nil
+// when TLS is disabled.
+func (c *RedisConfig) tlsConfig() *tls.Config {
+	if !c.TLSEnabled {
+		return nil
+	}
+	return &tls.Config{InsecureSkipVerify: c.TLSInsecureSkipVerify}
+}
+
+func splitAndTrim(s string) []string {
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+func envInt(key string) (int, bool) {
+	val := os.Getenv(key)
+	if val == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func envBool(key string) (bool, bool) {
+	val := os.Getenv(key)
+	if val == "" {
+		return false, false
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, false
+	}
+	return b, true
+}
+
+func envDuration(key string) (time.Duration, bool) {
+	val := os.Getenv(key)
+	if val == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"backend/models"
+)
+
+// taskCancelTTL bounds how long a task:{uuid}:cancel marker lives. The
+// task's own status:{uuid} and url_task:{url} keys are stored without an
+// expiry, so the marker can expire while the task's keys remain.
+const taskCancelTTL = 1 * time.Hour
+
+func taskCancelKey(taskUUID string) string {
+	return "task:" + taskUUID + ":cancel"
+}
+
+// RequestTaskCancellation flags taskUUID for cooperative cancellation. The
+// worker processing it is expected to poll IsTaskCancelled between
+// pipeline stages and honor ctx.Done() on its outbound HTTP/LLM calls so
+// it can exit promptly and mark the task "cancelled" itself.
+func RequestTaskCancellation(taskUUID string) error {
+	return rdb.Set(ctx, taskCancelKey(taskUUID), "1", taskCancelTTL).Err()
+}
+
+// IsTaskCancelled reports whether taskUUID has been flagged for cancellation.
+func IsTaskCancelled(taskUUID string) (bool, error) {
+	_, err := rdb.Get(ctx, taskCancelKey(taskUUID)).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindURLByTaskUUID scans url_task:* mappings for the URL associated with
+// taskUUID. It cannot use the uuid_url index, since that index is only
+// written once a result is cached, which a pending task never reaches.
+func FindURLByTaskUUID(taskUUID string) (string, error) {
+	keys, err := GetAllURLTaskKeys()
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keys {
+		data, err := rdb.Get(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+		var mapping models.URLTaskMapping
+		if err := json.Unmarshal([]byte(data), &mapping); err != nil {
+			continue
+		}
+		if mapping.UUID == taskUUID {
+			return key[len("url_task:"):], nil
+		}
+	}
+	return "", fmt.Errorf("no task found for UUID: %s", taskUUID)
+}
+
+// CancelPendingTaskInQueue removes taskUUID's entry from the pending queue
+// if no worker has claimed it yet, for HandleCancelTask's force=true path.
+func CancelPendingTaskInQueue(taskUUID string) (bool, error) {
+	entries, err := rdb.LRange(ctx, "queue:tasks", 0, -1).Result()
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		var payload models.TaskPayload
+		if err := json.Unmarshal([]byte(entry), &payload); err != nil {
+			continue
+		}
+		if payload.UUID == taskUUID {
+			removed, err := rdb.LRem(ctx, "queue:tasks", 1, entry).Result()
+			return removed > 0, err
+		}
+	}
+	return false, nil
+}
+
+// DeleteURLTaskMapping removes the url_task:{url} mapping so the URL can
+// be resubmitted as a fresh task.
+func DeleteURLTaskMapping(url string) error {
+	return rdb.Del(ctx, "url_task:"+url).Err()
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+	"backend/models"
+)
+
+// taskEventsChannel is the Redis pub/sub channel task status changes for
+// uuid are published on, so HandleTaskStream can push them to subscribed
+// clients without polling.
+func taskEventsChannel(uuid string) string {
+	return "task:" + uuid + ":events"
+}
+
+// PublishTaskEvent publishes a task status change (queued, scraping,
+// summarizing, done, error, cancelling, cancelled, ...) on uuid's events
+// channel.
+func PublishTaskEvent(uuid string, event string) error {
+	payload, err := json.Marshal(models.TaskEvent{UUID: uuid, Event: event})
+	if err != nil {
+		return err
+	}
+	return rdb.Publish(ctx, taskEventsChannel(uuid), payload).Err()
+}
+
+// SubscribeTaskEvents subscribes to uuid's task events channel. The caller
+// is responsible for closing the returned subscription.
+func SubscribeTaskEvents(uuid string) *redis.PubSub {
+	return rdb.Subscribe(ctx, taskEventsChannel(uuid))
+}
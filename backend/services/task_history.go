@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"backend/models"
+)
+
+// tasksByTimeKey is the sorted set backing /tasks pagination, scored by
+// creation time (unix millis) so the most recent submissions sort first
+// without needing to scan every url_task:* key.
+const tasksByTimeKey = "tasks:by_time"
+
+// RecordTaskCreated adds url to the by-time index, scored by createdAt, so
+// GetURLTaskPage can page through task history newest-first.
+func RecordTaskCreated(url string, createdAt int64) error {
+	return rdb.ZAdd(ctx, tasksByTimeKey, redis.Z{Score: float64(createdAt), Member: url}).Err()
+}
+
+// TaskPageFilter narrows down GetURLTaskPage's results before pagination is
+// applied. An empty Status or Query matches everything.
+type TaskPageFilter struct {
+	Status string
+	Query  string
+}
+
+// GetURLTaskPage returns the page of URLs (newest first) matching filter,
+// along with the raw url_task:* mapping JSON for each and the total count
+// of matching tasks (before pagination).
+//
+// With no filter applied, the requested page bounds the Redis reads
+// directly via ZREVRANGE+ZCARD. Redis has no server-side way to filter a
+// sorted set by an arbitrary substring or a value stored in a separate
+// key, so once a filter is present there's no way around fetching the
+// full by-time index and filtering it in application code before slicing
+// into the requested page.
+func GetURLTaskPage(offset, limit int, filter TaskPageFilter) (urls []string, mappingData []string, total int, err error) {
+	if filter.Status == "" && filter.Query == "" {
+		return getURLTaskPageUnfiltered(offset, limit)
+	}
+	return getURLTaskPageFiltered(offset, limit, filter)
+}
+
+// getURLTaskPageUnfiltered serves the common case: it bounds the Redis
+// read to exactly the requested page instead of loading the whole index.
+func getURLTaskPageUnfiltered(offset, limit int) (urls []string, mappingData []string, total int, err error) {
+	total64, err := rdb.ZCard(ctx, tasksByTimeKey).Result()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	total = int(total64)
+	if offset >= total {
+		return nil, nil, total, nil
+	}
+
+	end := offset + limit - 1
+	pageURLs, err := rdb.ZRevRange(ctx, tasksByTimeKey, int64(offset), int64(end)).Result()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(pageURLs) == 0 {
+		return nil, nil, total, nil
+	}
+
+	keys := make([]string, len(pageURLs))
+	for i, url := range pageURLs {
+		keys[i] = "url_task:" + url
+	}
+	rawValues, err := rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	pageData := make([]string, len(pageURLs))
+	for i, raw := range rawValues {
+		if data, ok := raw.(string); ok {
+			pageData[i] = data
+		}
+	}
+	return pageURLs, pageData, total, nil
+}
+
+// getURLTaskPageFiltered is the slow path: Redis can't filter
+// tasks:by_time by status or URL substring server-side, so the whole
+// index is fetched and filtered in application code before being sliced
+// into the requested page.
+func getURLTaskPageFiltered(offset, limit int, filter TaskPageFilter) (urls []string, mappingData []string, total int, err error) {
+	allURLs, err := rdb.ZRevRange(ctx, tasksByTimeKey, 0, -1).Result()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(allURLs) == 0 {
+		return nil, nil, 0, nil
+	}
+
+	keys := make([]string, len(allURLs))
+	for i, url := range allURLs {
+		keys[i] = "url_task:" + url
+	}
+	rawValues, err := rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var matchedURLs []string
+	var matchedData []string
+	for i, raw := range rawValues {
+		data, ok := raw.(string)
+		if !ok {
+			continue
+		}
+
+		var mapping models.URLTaskMapping
+		if err := json.Unmarshal([]byte(data), &mapping); err != nil {
+			continue
+		}
+		if filter.Status != "" && mapping.Status != filter.Status {
+			continue
+		}
+		if filter.Query != "" && !strings.Contains(strings.ToLower(allURLs[i]), strings.ToLower(filter.Query)) {
+			continue
+		}
+		matchedURLs = append(matchedURLs, allURLs[i])
+		matchedData = append(matchedData, data)
+	}
+
+	total = len(matchedURLs)
+	if offset >= total {
+		return nil, nil, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return matchedURLs[offset:end], matchedData[offset:end], total, nil
+}
@@ -0,0 +1,161 @@
+package urlguard
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{"public IPv4", "93.184.216.34", false},
+		{"loopback IPv4", "127.0.0.1", true},
+		{"RFC1918 10/8", "10.0.0.5", true},
+		{"RFC1918 172.16/12", "172.16.5.5", true},
+		{"RFC1918 192.168/16", "192.168.1.1", true},
+		{"link-local IPv4", "169.254.169.254", true}, // cloud metadata endpoint
+		{"CGNAT 100.64/10", "100.64.0.1", true},
+		{"unspecified IPv4", "0.0.0.0", true},
+		{"public IPv6", "2606:4700:4700::1111", false},
+		{"loopback IPv6", "::1", true},
+		{"link-local IPv6", "fe80::1", true},
+		{"ULA IPv6", "fd00::1", true},
+		{"unspecified IPv6", "::", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if ip == nil {
+				t.Fatalf("failed to parse test IP %s", tt.ip)
+			}
+			if got := isBlockedIP(ip); got != tt.want {
+				t.Errorf("isBlockedIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardCheckURL(t *testing.T) {
+	origLookup := lookupIP
+	defer func() { lookupIP = origLookup }()
+
+	tests := []struct {
+		name      string
+		url       string
+		resolved  []net.IP
+		lookupErr error
+		wantErr   bool
+	}{
+		{
+			name:     "public host resolves to public IP",
+			url:      "https://example.com",
+			resolved: []net.IP{net.ParseIP("93.184.216.34")},
+			wantErr:  false,
+		},
+		{
+			name:     "dns-rebinding host resolves to loopback",
+			url:      "https://attacker.example",
+			resolved: []net.IP{net.ParseIP("127.0.0.1")},
+			wantErr:  true,
+		},
+		{
+			name:     "dns-rebinding host resolves to cloud metadata link-local",
+			url:      "https://attacker.example",
+			resolved: []net.IP{net.ParseIP("169.254.169.254")},
+			wantErr:  true,
+		},
+		{
+			name:     "host resolves to a mix of public and private IPs is blocked",
+			url:      "https://multi-homed.example",
+			resolved: []net.IP{net.ParseIP("93.184.216.34"), net.ParseIP("10.0.0.1")},
+			wantErr:  true,
+		},
+		{
+			name:     "non-standard port is rejected",
+			url:      "https://example.com:8080",
+			resolved: []net.IP{net.ParseIP("93.184.216.34")},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupIP = func(host string) ([]net.IP, error) {
+				return tt.resolved, tt.lookupErr
+			}
+
+			guard := New(&Config{AllowedPorts: map[string]bool{"443": true}, MaxRedirects: defaultMaxRedirects})
+			err := guard.CheckURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckURL(%s) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGuardDialContextRevalidatesOnEachDial covers the gap a single
+// upfront CheckURL call leaves open: if the host resolves differently by
+// the time the real connection is dialed (DNS rebinding), the dial must
+// still be blocked rather than trusting CheckURL's earlier answer.
+func TestGuardDialContextRevalidatesOnEachDial(t *testing.T) {
+	origLookup := lookupIP
+	defer func() { lookupIP = origLookup }()
+
+	var calls int
+	lookupIP = func(host string) ([]net.IP, error) {
+		calls++
+		if calls == 1 {
+			// The upfront CheckURL validation sees a public IP...
+			return []net.IP{net.ParseIP("93.184.216.34")}, nil
+		}
+		// ...but by the time the real connection dials, DNS has rebound
+		// to an internal address.
+		return []net.IP{net.ParseIP("169.254.169.254")}, nil
+	}
+
+	guard := New(&Config{AllowedPorts: map[string]bool{"443": true}, MaxRedirects: defaultMaxRedirects})
+
+	if err := guard.CheckURL("https://attacker.example"); err != nil {
+		t.Fatalf("expected upfront check against the first (public) answer to pass, got %v", err)
+	}
+
+	if _, err := guard.dialContext(context.Background(), "tcp", "attacker.example:443"); err == nil {
+		t.Error("expected dialContext to re-resolve and block the rebound address rather than trusting CheckURL's earlier answer")
+	}
+}
+
+func TestGuardHostAllowDenyLists(t *testing.T) {
+	origLookup := lookupIP
+	defer func() { lookupIP = origLookup }()
+	lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("93.184.216.34")}, nil
+	}
+
+	t.Run("denied host is rejected even if it resolves publicly", func(t *testing.T) {
+		guard := New(&Config{
+			DeniedHosts:  map[string]bool{"blocked.example": true},
+			AllowedPorts: map[string]bool{"443": true},
+		})
+		if err := guard.CheckURL("https://blocked.example"); err == nil {
+			t.Error("expected denied host to be rejected")
+		}
+	})
+
+	t.Run("allowlist rejects hosts not on it", func(t *testing.T) {
+		guard := New(&Config{
+			AllowedHosts: map[string]bool{"allowed.example": true},
+			AllowedPorts: map[string]bool{"443": true},
+		})
+		if err := guard.CheckURL("https://not-allowed.example"); err == nil {
+			t.Error("expected host outside the allowlist to be rejected")
+		}
+		if err := guard.CheckURL("https://allowed.example"); err != nil {
+			t.Errorf("expected allowlisted host to pass, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/models"
+)
+
+// UUIDIndexReconcileInterval is how often main starts
+// StartUUIDIndexReconciler ticking to run ReconcileUUIDIndex in the
+// background.
+const UUIDIndexReconcileInterval = 5 * time.Minute
+
+// StartUUIDIndexReconciler runs ReconcileUUIDIndex once immediately and
+// then on every tick of interval, until the process exits. It must be
+// started in its own goroutine. This is the only caller of
+// ReconcileUUIDIndex: lookupCacheByUUID treats a missing index entry as a
+// plain miss rather than reconciling inline, so a cache:* scan never runs
+// on the request path.
+func StartUUIDIndexReconciler(interval time.Duration) {
+	if err := ReconcileUUIDIndex(); err != nil {
+		log.Printf("Failed to reconcile uuid_url index: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := ReconcileUUIDIndex(); err != nil {
+			log.Printf("Failed to reconcile uuid_url index: %v", err)
+		}
+	}
+}
+
+// ReconcileUUIDIndex rebuilds any missing uuid_url:{uuid} entries by
+// scanning cache:* (via SCAN, not KEYS) and writing back the index with
+// the same TTL as the cache entry it was derived from. It's a fallback
+// path for lookupCacheByUUID, so it's expected to run occasionally rather
+// than on every lookup.
+func ReconcileUUIDIndex() error {
+	cacheKeys, err := scanKeys("cache:*")
+	if err != nil {
+		return fmt.Errorf("failed to scan cache keys: %v", err)
+	}
+
+	rebuilt := 0
+	for _, cacheKey := range cacheKeys {
+		url := cacheKey[len("cache:"):]
+
+		cachedData, err := rdb.Get(ctx, cacheKey).Result()
+		if err != nil {
+			continue
+		}
+
+		var result models.ProcessResult
+		if err := json.Unmarshal([]byte(cachedData), &result); err != nil {
+			continue
+		}
+		if result.UUID == "" {
+			continue
+		}
+
+		indexKey := "uuid_url:" + result.UUID
+		if exists, err := rdb.Exists(ctx, indexKey).Result(); err == nil && exists > 0 {
+			continue
+		}
+
+		ttl, err := rdb.TTL(ctx, cacheKey).Result()
+		if err != nil || ttl <= 0 {
+			continue
+		}
+
+		if err := rdb.Set(ctx, indexKey, url, ttl).Err(); err != nil {
+			log.Printf("Failed to rebuild uuid_url index for %s: %v", result.UUID, err)
+			continue
+		}
+		rebuilt++
+	}
+
+	if rebuilt > 0 {
+		log.Printf("Reconciled %d uuid_url index entries", rebuilt)
+	}
+	return nil
+}
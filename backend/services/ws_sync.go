@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"backend/websocket"
+)
+
+// wsSyncChannel is the Redis pub/sub channel every backend instance
+// publishes its outbound WebSocket frames to and subscribes from, so
+// horizontally scaled instances behind a load balancer all deliver
+// task_update / chat_response frames to the clients connected to them.
+const wsSyncChannel = "augscraper:ws_sync"
+
+// instanceID identifies this process so its own publishes can be told
+// apart from a peer's when they arrive back over the sync channel.
+var instanceID = uuid.New().String()
+
+// wsSyncEnvelope wraps an outbound WebSocket frame with the originating
+// instance ID. UUID is set only for frames scoped to a single task UUID
+// (see BroadcastToUUID); it's empty for frames meant for every client.
+type wsSyncEnvelope struct {
+	Origin  string          `json:"origin"`
+	UUID    string          `json:"uuid,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// BroadcastWS delivers payload to this instance's local WebSocket clients
+// and fans it out to every other instance via the sync channel.
+func BroadcastWS(hub *websocket.Hub, payload []byte) {
+	select {
+	case hub.GetBroadcastChannel() <- payload:
+	default:
+		log.Printf("WebSocket broadcast channel full, dropping local frame")
+	}
+
+	publishWSSyncEnvelope(wsSyncEnvelope{Origin: instanceID, Payload: payload})
+}
+
+// BroadcastToUUID delivers payload to this instance's local clients
+// subscribed to uuid and fans it out to every other instance via the sync
+// channel, so a task's WebSocket subscriber is reached regardless of
+// which instance is streaming frames for it (e.g. chat_token deltas).
+func BroadcastToUUID(hub *websocket.Hub, uuid string, payload []byte) {
+	hub.SendToUUID(uuid, payload)
+	publishWSSyncEnvelope(wsSyncEnvelope{Origin: instanceID, UUID: uuid, Payload: payload})
+}
+
+// publishWSSyncEnvelope marshals and publishes envelope on wsSyncChannel.
+func publishWSSyncEnvelope(envelope wsSyncEnvelope) {
+	envelopeJSON, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("Failed to marshal WebSocket sync envelope: %v", err)
+		return
+	}
+
+	if err := rdb.Publish(ctx, wsSyncChannel, envelopeJSON).Err(); err != nil {
+		log.Printf("Failed to publish WebSocket sync envelope: %v", err)
+	}
+}
+
+// StartWSSyncSubscriber subscribes to the cross-instance sync channel and
+// forwards frames published by other instances to this instance's local
+// clients - UUID-scoped frames to the clients subscribed to that UUID,
+// everything else to every local client. Frames this instance originated
+// are skipped since BroadcastWS/BroadcastToUUID already delivered them
+// locally.
+func StartWSSyncSubscriber(hub *websocket.Hub) {
+	log.Printf("Starting Redis pub/sub subscriber for %s...\n", wsSyncChannel)
+
+	pubsub := rdb.Subscribe(ctx, wsSyncChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+
+	for msg := range ch {
+		var envelope wsSyncEnvelope
+		if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil {
+			log.Printf("Failed to parse WebSocket sync envelope: %v", err)
+			continue
+		}
+
+		if envelope.Origin == instanceID {
+			continue // already delivered locally by BroadcastWS/BroadcastToUUID
+		}
+
+		if envelope.UUID != "" {
+			hub.SendToUUID(envelope.UUID, []byte(envelope.Payload))
+			continue
+		}
+
+		select {
+		case hub.GetBroadcastChannel() <- []byte(envelope.Payload):
+		default:
+			log.Printf("WebSocket broadcast channel full, dropping frame from instance %s", envelope.Origin)
+		}
+	}
+}
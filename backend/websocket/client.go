@@ -0,0 +1,128 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	sendBufferSize = 256
+)
+
+// Client is a single WebSocket connection registered with a Hub.
+type Client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+
+	uuidMu sync.RWMutex
+	uuid   string // task UUID this client wants chat_token frames for, if any
+}
+
+// NewClient wraps conn as a Client of hub.
+func NewClient(hub *Hub, conn *websocket.Conn) *Client {
+	return &Client{
+		hub:  hub,
+		conn: conn,
+		send: make(chan []byte, sendBufferSize),
+	}
+}
+
+// GetSendChannel returns the channel used to queue frames for this client.
+func (c *Client) GetSendChannel() chan []byte {
+	return c.send
+}
+
+// GetUUID returns the task UUID this client is currently subscribed to, or
+// "" if it hasn't subscribed to one.
+func (c *Client) GetUUID() string {
+	c.uuidMu.RLock()
+	defer c.uuidMu.RUnlock()
+	return c.uuid
+}
+
+// SetUUID records the task UUID this client wants chat_token frames for.
+func (c *Client) SetUUID(uuid string) {
+	c.uuidMu.Lock()
+	c.uuid = uuid
+	c.uuidMu.Unlock()
+}
+
+// clientSubscribeMessage is the inbound control frame a client sends to
+// subscribe to a task's chat_token stream.
+type clientSubscribeMessage struct {
+	Type string `json:"type"`
+	UUID string `json:"uuid"`
+}
+
+// ReadPump reads inbound frames, honoring a {"type":"subscribe","uuid":"..."}
+// control frame so the client only receives chat_token frames for its own
+// in-flight chat request, and unregisters the client once the connection
+// drops so any stream it was subscribed to can be aborted.
+func (c *Client) ReadPump() {
+	defer func() {
+		c.hub.unregister <- c
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Printf("WebSocket read error: %v", err)
+			}
+			break
+		}
+
+		var subscribe clientSubscribeMessage
+		if err := json.Unmarshal(message, &subscribe); err != nil {
+			continue
+		}
+		if subscribe.Type == "subscribe" && subscribe.UUID != "" {
+			c.SetUUID(subscribe.UUID)
+		}
+	}
+}
+
+// WritePump delivers queued frames to the client and keeps the connection
+// alive with periodic pings until send is closed or a write fails.
+func (c *Client) WritePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				log.Printf("WebSocket write error: %v", err)
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// broadcastBufferSize bounds how many frames can be queued for delivery
+// before a slow consumer starts causing drops instead of blocking senders.
+const broadcastBufferSize = 256
+
+// uuidMessage is a frame scoped to the client(s) subscribed to a single
+// task UUID, e.g. a chat_token delta that only the requesting client
+// should see.
+type uuidMessage struct {
+	uuid    string
+	payload []byte
+}
+
+// Hub maintains the set of active WebSocket clients and fans outbound
+// frames out to all of them.
+type Hub struct {
+	clients    map[*Client]bool
+	broadcast  chan []byte
+	uuidSend   chan uuidMessage
+	register   chan *Client
+	unregister chan *Client
+
+	cancelFuncsMu sync.Mutex
+	cancelFuncs   map[string]context.CancelFunc
+}
+
+// NewHub creates a Hub ready to be started with Run.
+func NewHub() *Hub {
+	return &Hub{
+		clients:     make(map[*Client]bool),
+		broadcast:   make(chan []byte, broadcastBufferSize),
+		uuidSend:    make(chan uuidMessage, broadcastBufferSize),
+		register:    make(chan *Client),
+		unregister:  make(chan *Client),
+		cancelFuncs: make(map[string]context.CancelFunc),
+	}
+}
+
+// GetBroadcastChannel returns the channel used to fan a frame out to every
+// locally connected client.
+func (h *Hub) GetBroadcastChannel() chan []byte {
+	return h.broadcast
+}
+
+// GetRegisterChannel returns the channel new clients are registered on.
+func (h *Hub) GetRegisterChannel() chan *Client {
+	return h.register
+}
+
+// GetUnregisterChannel returns the channel clients are removed on.
+func (h *Hub) GetUnregisterChannel() chan *Client {
+	return h.unregister
+}
+
+// SendToUUID delivers payload only to clients subscribed to uuid (see
+// Client.SetUUID), e.g. chat_token deltas that only the requesting client
+// should see.
+func (h *Hub) SendToUUID(uuid string, payload []byte) {
+	select {
+	case h.uuidSend <- uuidMessage{uuid: uuid, payload: payload}:
+	default:
+		log.Printf("WebSocket uuid-scoped send buffer full, dropping frame for UUID %s", uuid)
+	}
+}
+
+// RegisterCancel associates uuid with cancel so that if every client
+// subscribed to it disconnects, the in-flight stream producing it (e.g. a
+// streaming chat completion) can be aborted.
+func (h *Hub) RegisterCancel(uuid string, cancel context.CancelFunc) {
+	h.cancelFuncsMu.Lock()
+	h.cancelFuncs[uuid] = cancel
+	h.cancelFuncsMu.Unlock()
+}
+
+// UnregisterCancel removes uuid's cancel func without calling it, e.g. once
+// its stream has finished normally.
+func (h *Hub) UnregisterCancel(uuid string) {
+	h.cancelFuncsMu.Lock()
+	delete(h.cancelFuncs, uuid)
+	h.cancelFuncsMu.Unlock()
+}
+
+// cancelUUID calls and removes uuid's registered cancel func, if any.
+func (h *Hub) cancelUUID(uuid string) {
+	h.cancelFuncsMu.Lock()
+	cancel, ok := h.cancelFuncs[uuid]
+	if ok {
+		delete(h.cancelFuncs, uuid)
+	}
+	h.cancelFuncsMu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// Run processes client registration and broadcast frames until the
+// process exits. It must be started in its own goroutine.
+func (h *Hub) Run() {
+	for {
+		select {
+		case client := <-h.register:
+			h.clients[client] = true
+		case client := <-h.unregister:
+			if _, ok := h.clients[client]; ok {
+				delete(h.clients, client)
+				close(client.send)
+				if uuid := client.GetUUID(); uuid != "" {
+					stillSubscribed := false
+					for other := range h.clients {
+						if other.GetUUID() == uuid {
+							stillSubscribed = true
+							break
+						}
+					}
+					if !stillSubscribed {
+						h.cancelUUID(uuid)
+					}
+				}
+			}
+		case message := <-h.broadcast:
+			for client := range h.clients {
+				select {
+				case client.send <- message:
+				default:
+					log.Printf("WebSocket client send buffer full, dropping client")
+					close(client.send)
+					delete(h.clients, client)
+				}
+			}
+		case msg := <-h.uuidSend:
+			for client := range h.clients {
+				if client.GetUUID() != msg.uuid {
+					continue
+				}
+				select {
+				case client.send <- msg.payload:
+				default:
+					log.Printf("WebSocket client send buffer full, dropping client")
+					close(client.send)
+					delete(h.clients, client)
+				}
+			}
+		}
+	}
+}